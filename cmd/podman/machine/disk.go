@@ -0,0 +1,167 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/containers/common/pkg/strongunits"
+	"github.com/containers/podman/v5/cmd/podman/registry"
+	"github.com/containers/podman/v5/pkg/machine/define"
+	"github.com/containers/podman/v5/pkg/machine/qemu"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diskCmd = &cobra.Command{
+		Use:   "disk",
+		Short: "Manage a machine's additional disks",
+		Long:  "Add, remove, and resize the additional disks attached to a machine",
+	}
+
+	diskAddOpts struct {
+		Image    string
+		SizeGB   uint64
+		Format   string
+		Bus      string
+		Cache    string
+		AIO      string
+		Serial   string
+		Discard  bool
+		ReadOnly bool
+	}
+
+	diskAddCmd = &cobra.Command{
+		Use:   "add NAME",
+		Short: "Add an additional disk to a machine",
+		Long:  "Attach a new additional disk to a machine, either blank or imported from an existing image",
+		RunE:  diskAdd,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	diskRemoveCmd = &cobra.Command{
+		Use:   "remove NAME",
+		Short: "Remove an additional disk from a machine",
+		RunE:  diskRemove,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	diskResizeSizeGB uint64
+
+	diskResizeCmd = &cobra.Command{
+		Use:   "resize NAME",
+		Short: "Resize an additional disk",
+		Long:  "Grow an additional disk attached to a machine. The machine must be stopped",
+		RunE:  diskResize,
+		Args:  cobra.ExactArgs(1),
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands,
+		registry.CliCommand{Command: diskCmd, Parent: machineCmd},
+		registry.CliCommand{Command: diskAddCmd, Parent: diskCmd},
+		registry.CliCommand{Command: diskRemoveCmd, Parent: diskCmd},
+		registry.CliCommand{Command: diskResizeCmd, Parent: diskCmd},
+	)
+
+	addFlags := diskAddCmd.Flags()
+	addFlags.StringVar(&diskAddOpts.Image, "image", "", "Import an existing qcow2/raw/vmdk/vhdx image instead of creating a blank disk")
+	addFlags.Uint64Var(&diskAddOpts.SizeGB, "size", 20, "Disk size in GiB (ignored with --image)")
+	addFlags.StringVar(&diskAddOpts.Format, "format", string(vmconfigs.Qcow2), "Disk image format: qcow2, raw, vmdk, or vhdx")
+	addFlags.StringVar(&diskAddOpts.Bus, "bus", string(vmconfigs.VirtioBlkBus), "Disk bus: virtio-blk, nvme, or scsi")
+	addFlags.StringVar(&diskAddOpts.Cache, "cache", "", "qemu drive cache mode")
+	addFlags.StringVar(&diskAddOpts.AIO, "aio", "", "qemu drive AIO backend")
+	addFlags.StringVar(&diskAddOpts.Serial, "serial", "", "Disk serial number (defaults to the drive id)")
+	addFlags.BoolVar(&diskAddOpts.Discard, "discard", false, "Enable discard/TRIM passthrough")
+	addFlags.BoolVar(&diskAddOpts.ReadOnly, "read-only", false, "Attach the disk read-only")
+
+	diskResizeCmd.Flags().Uint64Var(&diskResizeSizeGB, "size", 0, "New disk size in GiB")
+}
+
+func diskAdd(_ *cobra.Command, args []string) error {
+	name := args[0]
+	mc, _, err := resolveMachine(defaultMachineName)
+	if err != nil {
+		return err
+	}
+
+	provider := &qemu.QEMUStubber{}
+	dirs, err := define.NewMachineDirs(mc.Name)
+	if err != nil {
+		return err
+	}
+
+	disk := vmconfigs.DiskConfig{
+		Name:     name,
+		Format:   vmconfigs.DiskFormat(diskAddOpts.Format),
+		Bus:      vmconfigs.DiskBus(diskAddOpts.Bus),
+		Cache:    diskAddOpts.Cache,
+		AIO:      diskAddOpts.AIO,
+		Serial:   diskAddOpts.Serial,
+		Discard:  diskAddOpts.Discard,
+		ReadOnly: diskAddOpts.ReadOnly,
+	}
+
+	if diskAddOpts.Image != "" {
+		converted, err := provider.GetAdditionalDisk(diskAddOpts.Image, dirs, mc, name, disk.Format)
+		if err != nil {
+			return err
+		}
+		disk.Path = converted
+		mc.Disks = append(mc.Disks, disk)
+	} else {
+		disk.Size = diskAddOpts.SizeGB
+		if err := provider.AddDisk(mc, dirs, disk); err != nil {
+			return err
+		}
+	}
+
+	if err := mc.Write(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Disk %q added to machine %q\n", name, mc.Name)
+	return nil
+}
+
+func diskRemove(_ *cobra.Command, args []string) error {
+	name := args[0]
+	mc, _, err := resolveMachine(defaultMachineName)
+	if err != nil {
+		return err
+	}
+
+	provider := &qemu.QEMUStubber{}
+	if err := provider.RemoveDisk(mc, name); err != nil {
+		return err
+	}
+	if err := mc.Write(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Disk %q removed from machine %q\n", name, mc.Name)
+	return nil
+}
+
+func diskResize(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if diskResizeSizeGB == 0 {
+		return fmt.Errorf("--size is required")
+	}
+
+	mc, _, err := resolveMachine(defaultMachineName)
+	if err != nil {
+		return err
+	}
+
+	provider := &qemu.QEMUStubber{}
+	if err := provider.ResizeDisk(mc, name, strongunits.GiB(diskResizeSizeGB)); err != nil {
+		return err
+	}
+	if err := mc.Write(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Disk %q resized to %dGiB\n", name, diskResizeSizeGB)
+	return nil
+}
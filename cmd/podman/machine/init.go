@@ -0,0 +1,71 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/containers/podman/v5/cmd/podman/registry"
+	"github.com/containers/podman/v5/pkg/machine/define"
+	"github.com/containers/podman/v5/pkg/machine/qemu"
+	"github.com/containers/podman/v5/pkg/machine/shim"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initOpts shim.InitOptions
+	firmware string
+
+	initCmd = &cobra.Command{
+		Use:   "init [MACHINE]",
+		Short: "Initialize a virtual machine",
+		Long:  "Initialize a new virtual machine for running containers and pods",
+		RunE:  initMachine,
+		Args:  cobra.MaximumNArgs(1),
+		Example: `podman machine init
+podman machine init --cpus 4 --memory 4096 --firmware uefi-secure --tpm`,
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: initCmd,
+		Parent:  machineCmd,
+	})
+
+	flags := initCmd.Flags()
+	flags.Uint64Var(&initOpts.CPUs, "cpus", 1, "Number of CPUs")
+	flags.Uint64Var(&initOpts.DiskGB, "disk-size", 100, "Disk size in GiB")
+	flags.Uint64Var(&initOpts.MemoryMB, "memory", 2048, "Memory in MiB")
+	flags.BoolVar(&initOpts.Rootful, "rootful", false, "Whether the machine should run rootful podman")
+	flags.StringVar(&firmware, "firmware", string(vmconfigs.BIOS), "Firmware to boot: bios, uefi, or uefi-secure")
+	flags.BoolVar(&initOpts.TPM, "tpm", false, "Request an emulated TPM (only valid with --firmware=uefi-secure, which always attaches one)")
+}
+
+func initMachine(_ *cobra.Command, args []string) error {
+	name := defaultMachineName
+	if len(args) > 0 {
+		name = args[0]
+	}
+	initOpts.Name = name
+
+	switch vmconfigs.Firmware(firmware) {
+	case vmconfigs.BIOS, vmconfigs.UEFI, vmconfigs.UEFISecure:
+		initOpts.Firmware = vmconfigs.Firmware(firmware)
+	default:
+		return fmt.Errorf("invalid --firmware %q: must be one of %q, %q, %q", firmware, vmconfigs.BIOS, vmconfigs.UEFI, vmconfigs.UEFISecure)
+	}
+
+	dirs, err := define.NewMachineDirs(name)
+	if err != nil {
+		return err
+	}
+
+	provider := &qemu.QEMUStubber{}
+	mc, err := shim.Init(initOpts, dirs, provider)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Machine %q created successfully\n", mc.Name)
+	return nil
+}
@@ -0,0 +1,51 @@
+package machine
+
+import (
+	"github.com/containers/podman/v5/cmd/podman/registry"
+	"github.com/containers/podman/v5/pkg/machine"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/spf13/cobra"
+)
+
+var machineCmd = &cobra.Command{
+	Use:   "machine",
+	Short: "Manage a virtual machine",
+	Long:  "Manage a virtual machine for running containers and pods",
+}
+
+// defaultMachineName is substituted whenever a machine subcommand is run
+// without an explicit MACHINE argument.
+const defaultMachineName = machine.DefaultMachineName
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: machineCmd,
+	})
+}
+
+// resolveMachine loads the named machine's config and provider the same
+// way every other machine subcommand does, defaulting to the active
+// machine when name is empty.
+func resolveMachine(name string) (*vmconfigs.MachineConfig, vmconfigs.VMStubber, error) {
+	return machine.LoadVMByName(name)
+}
+
+// autocompleteMachine completes MACHINE arguments with the names of the
+// machines that currently exist, so "podman machine pause <TAB>" behaves
+// like the rest of the machine subcommands.
+func autocompleteMachine(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, err := machine.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	suggestions := make([]string, 0, len(names))
+	for _, name := range names {
+		suggestions = append(suggestions, name.Name)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
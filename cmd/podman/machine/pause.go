@@ -0,0 +1,44 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/containers/podman/v5/cmd/podman/registry"
+	"github.com/containers/podman/v5/pkg/machine/shim"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:               "pause [MACHINE]",
+	Short:             "Pause a running machine",
+	Long:              "Freeze a running machine in place without shutting it down",
+	RunE:              pause,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: autocompleteMachine,
+	Example:           `podman machine pause podman-machine-default`,
+}
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: pauseCmd,
+		Parent:  machineCmd,
+	})
+}
+
+func pause(_ *cobra.Command, args []string) error {
+	name := defaultMachineName
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	mc, provider, err := resolveMachine(name)
+	if err != nil {
+		return err
+	}
+
+	if err := shim.Pause(mc, provider); err != nil {
+		return err
+	}
+	fmt.Printf("Machine %q paused successfully\n", name)
+	return nil
+}
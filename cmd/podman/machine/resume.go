@@ -0,0 +1,44 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/containers/podman/v5/cmd/podman/registry"
+	"github.com/containers/podman/v5/pkg/machine/shim"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:               "resume [MACHINE]",
+	Short:             "Resume a paused machine",
+	Long:              "Unfreeze a machine previously paused with 'podman machine pause'",
+	RunE:              resume,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: autocompleteMachine,
+	Example:           `podman machine resume podman-machine-default`,
+}
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: resumeCmd,
+		Parent:  machineCmd,
+	})
+}
+
+func resume(_ *cobra.Command, args []string) error {
+	name := defaultMachineName
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	mc, provider, err := resolveMachine(name)
+	if err != nil {
+		return err
+	}
+
+	if err := shim.Resume(mc, provider); err != nil {
+		return err
+	}
+	fmt.Printf("Machine %q resumed successfully\n", name)
+	return nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import "fmt"
+
+// DiskOptions carries the per-disk attributes AddDisk needs to build the
+// -drive/-device pair. It mirrors vmconfigs.DiskConfig without importing
+// it, keeping this package a leaf with respect to vmconfigs.
+type DiskOptions struct {
+	Format   string
+	Cache    string
+	AIO      string
+	Discard  bool
+	ReadOnly bool
+	Serial   string
+	Bus      string
+}
+
+// scsiControllerID is the id given to the single virtio-scsi-pci controller
+// shared by every scsi-bus disk, so EnsureSCSIController only ever adds it
+// once no matter how many scsi disks a machine has.
+const scsiControllerID = "virtio-scsi"
+
+// AddDisk emits the -drive/-device pair for one additional disk: a named
+// backend ("if=none") referenced by a bus-appropriate frontend device, so
+// serial numbers and per-disk cache/aio/discard options can be set
+// independently of the boot disk.
+//
+// Every disk gets a serial: nvme refuses to attach without one, and for the
+// other buses it's what lets the guest tell additional disks apart, so
+// AddDisk defaults it to the disk's drive id when the caller didn't set one.
+func (q *QemuCmd) AddDisk(id, path string, opts DiskOptions) {
+	drive := fmt.Sprintf("if=none,id=%s,file=%s,format=%s", id, path, opts.Format)
+	if opts.Cache != "" {
+		drive += ",cache=" + opts.Cache
+	}
+	if opts.AIO != "" {
+		drive += ",aio=" + opts.AIO
+	}
+	if opts.Discard {
+		drive += ",discard=unmap"
+	}
+	if opts.ReadOnly {
+		drive += ",readonly=on"
+	}
+
+	serial := opts.Serial
+	if serial == "" {
+		serial = id
+	}
+
+	device := fmt.Sprintf("%s,drive=%s,serial=%s", diskBusDevice(opts.Bus), id, serial)
+	if opts.Bus == "scsi" {
+		device += ",bus=" + scsiControllerID + ".0"
+	}
+
+	*q = append(*q, "-drive", drive, "-device", device)
+}
+
+// EnsureSCSIController adds the virtio-scsi-pci controller that scsi-bus
+// disks attach to, if one hasn't already been added. Callers building the
+// command line should invoke this once before any AddDisk call that uses
+// the scsi bus.
+func (q *QemuCmd) EnsureSCSIController() {
+	controller := "virtio-scsi-pci,id=" + scsiControllerID
+	for _, arg := range *q {
+		if arg == controller {
+			return
+		}
+	}
+	*q = append(*q, "-device", controller)
+}
+
+func diskBusDevice(bus string) string {
+	switch bus {
+	case "nvme":
+		return "nvme"
+	case "scsi":
+		return "scsi-hd"
+	default:
+		return "virtio-blk-pci"
+	}
+}
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddDiskSerial(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       DiskOptions
+		wantSerial string
+	}{
+		{
+			name:       "nvme disk without an explicit serial defaults to the drive id",
+			opts:       DiskOptions{Format: "raw", Bus: "nvme"},
+			wantSerial: "serial=disk1",
+		},
+		{
+			name:       "explicit serial is kept as-is",
+			opts:       DiskOptions{Format: "raw", Bus: "nvme", Serial: "mydisk"},
+			wantSerial: "serial=mydisk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q QemuCmd
+			q.AddDisk("disk1", "/var/lib/disk1.raw", tt.opts)
+
+			device := findDevice(t, q)
+			if !strings.Contains(device, tt.wantSerial) {
+				t.Errorf("device %q does not contain %q", device, tt.wantSerial)
+			}
+		})
+	}
+}
+
+func TestAddDiskBusDevice(t *testing.T) {
+	tests := []struct {
+		bus        string
+		wantDevice string
+	}{
+		{bus: "nvme", wantDevice: "nvme"},
+		{bus: "scsi", wantDevice: "scsi-hd"},
+		{bus: "virtio-blk", wantDevice: "virtio-blk-pci"},
+		{bus: "", wantDevice: "virtio-blk-pci"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bus, func(t *testing.T) {
+			var q QemuCmd
+			q.AddDisk("disk1", "/var/lib/disk1.raw", DiskOptions{Format: "raw", Bus: tt.bus})
+
+			device := findDevice(t, q)
+			if !strings.HasPrefix(device, tt.wantDevice+",") {
+				t.Errorf("device %q does not start with %q", device, tt.wantDevice+",")
+			}
+		})
+	}
+}
+
+func TestAddDiskSCSIAttachesToController(t *testing.T) {
+	var q QemuCmd
+	q.AddDisk("disk1", "/var/lib/disk1.raw", DiskOptions{Format: "raw", Bus: "scsi"})
+
+	device := findDevice(t, q)
+	if !strings.Contains(device, "bus="+scsiControllerID+".0") {
+		t.Errorf("device %q does not reference the scsi controller", device)
+	}
+}
+
+func TestEnsureSCSIControllerIsIdempotent(t *testing.T) {
+	var q QemuCmd
+	q.EnsureSCSIController()
+	q.EnsureSCSIController()
+
+	count := 0
+	for _, arg := range q {
+		if strings.HasPrefix(arg, "virtio-scsi-pci,id=") {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one virtio-scsi-pci controller, got %d in %v", count, q)
+	}
+}
+
+// findDevice returns the argument following the first "-device" flag.
+func findDevice(t *testing.T, q QemuCmd) string {
+	t.Helper()
+	for i, arg := range q {
+		if arg == "-device" && i+1 < len(q) {
+			return q[i+1]
+		}
+	}
+	t.Fatalf("no -device flag found in %v", q)
+	return ""
+}
@@ -0,0 +1,25 @@
+package command
+
+import "fmt"
+
+// SetFirmware points the VM at a UEFI firmware build: codePath holds the
+// read-only firmware code, varsPath the per-machine NVRAM variable store
+// (a copy of the firmware's VARS template, so Secure Boot enrollment and
+// other NVRAM state persist across boots).
+func (q *QemuCmd) SetFirmware(codePath, varsPath string) {
+	*q = append(*q,
+		"-drive", fmt.Sprintf("if=pflash,format=raw,readonly=on,file=%s", codePath),
+		"-drive", fmt.Sprintf("if=pflash,format=raw,file=%s", varsPath),
+	)
+}
+
+// SetTPM wires up an emulated TPM backed by an external swtpm process
+// listening on socketPath. deviceModel is "tpm-tis" on most platforms and
+// "tpm-crb" on aarch64.
+func (q *QemuCmd) SetTPM(socketPath, deviceModel string) {
+	*q = append(*q,
+		"-chardev", fmt.Sprintf("socket,id=chrtpm,path=%s", socketPath),
+		"-tpmdev", "emulator,id=tpm0,chardev=chrtpm",
+		"-device", fmt.Sprintf("%s,tpmdev=tpm0", deviceModel),
+	)
+}
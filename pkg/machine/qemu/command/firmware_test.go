@@ -0,0 +1,37 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetFirmware(t *testing.T) {
+	var q QemuCmd
+	q.SetFirmware("/usr/share/OVMF/OVMF_CODE.fd", "/run/podman-machine-default_VARS.fd")
+
+	joined := strings.Join(q, " ")
+	for _, want := range []string{
+		"if=pflash,format=raw,readonly=on,file=/usr/share/OVMF/OVMF_CODE.fd",
+		"if=pflash,format=raw,file=/run/podman-machine-default_VARS.fd",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("command line %q does not contain %q", joined, want)
+		}
+	}
+}
+
+func TestSetTPM(t *testing.T) {
+	var q QemuCmd
+	q.SetTPM("/run/podman-machine-default_swtpm.sock", "tpm-crb")
+
+	joined := strings.Join(q, " ")
+	for _, want := range []string{
+		"socket,id=chrtpm,path=/run/podman-machine-default_swtpm.sock",
+		"emulator,id=tpm0,chardev=chrtpm",
+		"tpm-crb,tpmdev=tpm0",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("command line %q does not contain %q", joined, want)
+		}
+	}
+}
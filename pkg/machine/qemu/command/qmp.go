@@ -0,0 +1,126 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// QMPClient is a minimal client for the QEMU Machine Protocol monitor,
+// used to control a running VM after boot (the command line built by
+// QemuCmd only gets the VM started).
+type QMPClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+type qmpGreeting struct {
+	QMP struct {
+		Version      json.RawMessage `json:"version"`
+		Capabilities []string        `json:"capabilities"`
+	} `json:"QMP"`
+}
+
+type qmpCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments,omitempty"`
+}
+
+type qmpResponse struct {
+	Return json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+}
+
+// NewQMPClient dials the QMP monitor's unix socket and negotiates
+// capabilities, leaving the connection ready for Execute calls.
+func NewQMPClient(socketPath string) (*QMPClient, error) {
+	conn, err := net.DialTimeout("unix", socketPath, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("dialing QMP monitor: %w", err)
+	}
+	c := &QMPClient{conn: conn, reader: bufio.NewReader(conn)}
+
+	var greeting qmpGreeting
+	if err := c.readInto(&greeting); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("reading QMP greeting: %w", err)
+	}
+
+	if _, err := c.Execute("qmp_capabilities", nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *QMPClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *QMPClient) readInto(v interface{}) error {
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(line, v)
+}
+
+// Execute sends a QMP command and returns its raw "return" payload.
+func (c *QMPClient) Execute(command string, arguments interface{}) (json.RawMessage, error) {
+	payload, err := json.Marshal(qmpCommand{Execute: command, Arguments: arguments})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(append(payload, '\n')); err != nil {
+		return nil, fmt.Errorf("writing QMP command %q: %w", command, err)
+	}
+
+	var resp qmpResponse
+	if err := c.readInto(&resp); err != nil {
+		return nil, fmt.Errorf("reading QMP response to %q: %w", command, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("QMP command %q failed: %s", command, resp.Error.Desc)
+	}
+	return resp.Return, nil
+}
+
+// PowerDown requests an ACPI shutdown of the guest, allowing it to exit
+// cleanly instead of being killed outright.
+func (c *QMPClient) PowerDown() error {
+	_, err := c.Execute("system_powerdown", nil)
+	return err
+}
+
+// Stop pauses VM execution (the guest is frozen in place, not shut down).
+func (c *QMPClient) Stop() error {
+	_, err := c.Execute("stop", nil)
+	return err
+}
+
+// Cont resumes VM execution after Stop.
+func (c *QMPClient) Cont() error {
+	_, err := c.Execute("cont", nil)
+	return err
+}
+
+// HumanMonitorCommand runs a legacy HMP command line (e.g. "savevm foo")
+// through QMP's human-monitor-command passthrough, for functionality that
+// has no dedicated QMP command.
+func (c *QMPClient) HumanMonitorCommand(hmp string) (string, error) {
+	raw, err := c.Execute("human-monitor-command", map[string]string{"command-line": hmp})
+	if err != nil {
+		return "", err
+	}
+	var out string
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/containers/common/pkg/strongunits"
+)
+
+// SetVirtioFSMount wires up a virtiofsd-backed share: a chardev pointed at
+// the vhost-user socket virtiofsd is listening on, and a vhost-user-fs-pci
+// device exposing it to the guest under tag.
+func (q *QemuCmd) SetVirtioFSMount(tag, socketPath string) {
+	chardevID := "virtiofs-" + tag
+	*q = append(*q,
+		"-chardev", fmt.Sprintf("socket,id=%s,path=%s", chardevID, socketPath),
+		"-device", fmt.Sprintf("vhost-user-fs-pci,chardev=%s,tag=%s", chardevID, tag),
+	)
+}
+
+// EnableVirtioFSMemory backs the guest's RAM with a shared memory-backed
+// file, which vhost-user-fs-pci devices require in order to mmap files out
+// of the virtiofsd-managed directory directly into the guest's address
+// space. It must be called at most once, and only when at least one
+// virtiofs mount is configured.
+func (q *QemuCmd) EnableVirtioFSMemory(mem strongunits.MiB) {
+	*q = append(*q,
+		"-object", fmt.Sprintf("memory-backend-file,id=mem,size=%dM,mem-path=/dev/shm,share=on", mem),
+		"-numa", "node,memdev=mem",
+	)
+}
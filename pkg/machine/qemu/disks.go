@@ -0,0 +1,221 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/common/pkg/strongunits"
+	"github.com/containers/podman/v5/pkg/machine/define"
+	"github.com/containers/podman/v5/pkg/machine/qemu/command"
+	"github.com/containers/podman/v5/pkg/machine/shim/diskpull"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+)
+
+// setDisksCommandLine adds one -drive/-device pair per additional disk
+// configured on the machine, on top of the boot image already set by
+// setQEMUCommandLine.
+func (q *QEMUStubber) setDisksCommandLine(mc *vmconfigs.MachineConfig) {
+	for _, disk := range mc.Disks {
+		if disk.Bus == vmconfigs.SCSIBus {
+			q.Command.EnsureSCSIController()
+			break
+		}
+	}
+
+	for i, disk := range mc.Disks {
+		id := fmt.Sprintf("disk%d", i+1)
+		q.Command.AddDisk(id, disk.Path.GetPath(), command.DiskOptions{
+			Format:   string(disk.Format),
+			Cache:    disk.Cache,
+			AIO:      disk.AIO,
+			Discard:  disk.Discard,
+			ReadOnly: disk.ReadOnly,
+			Serial:   disk.Serial,
+			Bus:      string(disk.Bus),
+		})
+	}
+}
+
+// AddDisk creates a new additional disk image and attaches it to the
+// machine config; it takes effect the next time the VM is started.
+func (q *QEMUStubber) AddDisk(mc *vmconfigs.MachineConfig, dirs *define.MachineDirs, disk vmconfigs.DiskConfig) error {
+	if err := q.requireStopped(mc); err != nil {
+		return err
+	}
+
+	cfg, err := config.Default()
+	if err != nil {
+		return err
+	}
+	qemuImgPath, err := cfg.FindHelperBinary("qemu-img", true)
+	if err != nil {
+		return err
+	}
+
+	diskPath, err := dirs.DataDir.AppendToNewVMFile(mc.Name+"_"+disk.Name+"."+string(disk.Format), nil)
+	if err != nil {
+		return err
+	}
+	// AppendToNewVMFile already created an empty placeholder at this path;
+	// qemu-img create refuses to overwrite an existing file without an
+	// interactive "y/n" prompt, so clear it first.
+	if err := os.Remove(diskPath.GetPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	create := exec.Command(qemuImgPath, "create", "-f", string(disk.Format), diskPath.GetPath(), strconv.FormatUint(disk.Size, 10)+"G")
+	create.Stdout = os.Stdout
+	create.Stderr = os.Stderr
+	if err := create.Run(); err != nil {
+		return fmt.Errorf("creating disk %q: %w", disk.Name, err)
+	}
+
+	disk.Path = diskPath
+	mc.Disks = append(mc.Disks, disk)
+	return nil
+}
+
+// RemoveDisk detaches and deletes a previously added additional disk.
+func (q *QEMUStubber) RemoveDisk(mc *vmconfigs.MachineConfig, name string) error {
+	if err := q.requireStopped(mc); err != nil {
+		return err
+	}
+
+	for i, disk := range mc.Disks {
+		if disk.Name != name {
+			continue
+		}
+		if err := disk.Path.Delete(); err != nil {
+			return err
+		}
+		mc.Disks = append(mc.Disks[:i], mc.Disks[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("no such disk: %q", name)
+}
+
+// ResizeDisk grows an additional disk (by name) or, when name is empty, the
+// machine's boot image.
+func (q *QEMUStubber) ResizeDisk(mc *vmconfigs.MachineConfig, name string, newSize strongunits.GiB) error {
+	if err := q.requireStopped(mc); err != nil {
+		return err
+	}
+
+	if name == "" {
+		return q.resizeDisk(newSize, mc.ImagePath)
+	}
+	for i, disk := range mc.Disks {
+		if disk.Name != name {
+			continue
+		}
+		if err := q.resizeDisk(newSize, disk.Path); err != nil {
+			return err
+		}
+		mc.Disks[i].Size = uint64(newSize)
+		return nil
+	}
+	return fmt.Errorf("no such disk: %q", name)
+}
+
+// requireStopped mirrors the guard SetProviderAttrs uses: disk layout
+// changes need to be invisible to a running qemu process, so they're
+// refused unless the machine is fully stopped.
+func (q *QEMUStubber) requireStopped(mc *vmconfigs.MachineConfig) error {
+	state, err := q.State(mc, false)
+	if err != nil {
+		return err
+	}
+	if state != define.Stopped {
+		return errors.New("unable to change disks unless vm is stopped")
+	}
+	return nil
+}
+
+// qemuImgFormat reports the on-disk format of the image at path via
+// `qemu-img info`, so GetAdditionalDisk can skip converting an image that's
+// already in the requested format.
+func qemuImgFormat(path string) (vmconfigs.DiskFormat, error) {
+	cfg, err := config.Default()
+	if err != nil {
+		return "", err
+	}
+	qemuImgPath, err := cfg.FindHelperBinary("qemu-img", true)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(qemuImgPath, "info", "--output=json", path)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("inspecting disk image %q: %w", path, err)
+	}
+
+	var info struct {
+		Format string `json:"format"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return "", fmt.Errorf("parsing qemu-img info for %q: %w", path, err)
+	}
+	return vmconfigs.DiskFormat(info.Format), nil
+}
+
+// GetAdditionalDisk pulls or converts a user-supplied disk image (qcow2,
+// raw, vmdk, or vhdx) into the machine's data dir, converting it with
+// qemu-img when its format doesn't already match targetFormat.
+func (q *QEMUStubber) GetAdditionalDisk(userInputPath string, dirs *define.MachineDirs, mc *vmconfigs.MachineConfig, name string, targetFormat vmconfigs.DiskFormat) (*define.VMFile, error) {
+	pulled, err := dirs.DataDir.AppendToNewVMFile(mc.Name+"_"+name+".pulled", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := diskpull.GetDisk(userInputPath, dirs, pulled, q.VMType(), mc.Name); err != nil {
+		return nil, err
+	}
+
+	pulledFormat, err := qemuImgFormat(pulled.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if pulledFormat == targetFormat {
+		return pulled, nil
+	}
+
+	converted, err := dirs.DataDir.AppendToNewVMFile(mc.Name+"_"+name+"."+string(targetFormat), nil)
+	if err != nil {
+		return nil, err
+	}
+	// As in AddDisk, AppendToNewVMFile leaves an empty placeholder behind,
+	// and qemu-img convert would otherwise stop to ask before overwriting it.
+	if err := os.Remove(converted.GetPath()); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	cfg, err := config.Default()
+	if err != nil {
+		return nil, err
+	}
+	qemuImgPath, err := cfg.FindHelperBinary("qemu-img", true)
+	if err != nil {
+		return nil, err
+	}
+
+	convert := exec.Command(qemuImgPath, "convert", "-O", string(targetFormat), pulled.GetPath(), converted.GetPath())
+	convert.Stdout = os.Stdout
+	convert.Stderr = os.Stderr
+	if err := convert.Run(); err != nil {
+		return nil, fmt.Errorf("converting disk %q to %s: %w", name, targetFormat, err)
+	}
+	if err := pulled.Delete(); err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
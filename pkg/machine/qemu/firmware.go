@@ -0,0 +1,118 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"path/filepath"
+
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+)
+
+// firmwareSearchDirs lists the directories distro packages install OVMF/AAVMF
+// builds into. Unlike the qemu and qemu-img binaries, firmware images aren't
+// on $PATH, so they can't be found with cfg.FindHelperBinary.
+func firmwareSearchDirs() []string {
+	return []string{
+		"/usr/share/OVMF",
+		"/usr/share/AAVMF",
+		"/usr/share/edk2/ovmf",
+		"/usr/share/edk2/aarch64",
+		"/usr/share/qemu/firmware",
+	}
+}
+
+// firmwareCodeFile and firmwareVarsTemplate name the read-only firmware
+// build and its pristine NVRAM template, which differ by architecture.
+func firmwareCodeFile() string {
+	if runtime.GOARCH == "arm64" {
+		return "AAVMF_CODE.fd"
+	}
+	return "OVMF_CODE.fd"
+}
+
+func firmwareVarsTemplate() string {
+	if runtime.GOARCH == "arm64" {
+		return "AAVMF_VARS.fd"
+	}
+	return "OVMF_VARS.fd"
+}
+
+// tpmDeviceModel is tpm-crb on aarch64 (the TIS interface qemu otherwise
+// defaults to is x86-only) and tpm-tis everywhere else.
+func tpmDeviceModel() string {
+	if runtime.GOARCH == "arm64" {
+		return "tpm-crb"
+	}
+	return "tpm-tis"
+}
+
+// locateFirmwareFile looks for name in each of firmwareSearchDirs, in order,
+// and returns the first match.
+func locateFirmwareFile(name string) (string, error) {
+	for _, dir := range firmwareSearchDirs() {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s: no such file in %v", name, firmwareSearchDirs())
+}
+
+// findFirmware locates the OVMF/AAVMF code and VARS template on the host.
+// These are data files installed by distro firmware packages, not
+// executables, so unlike the qemu and qemu-img binaries they're searched for
+// under firmwareSearchDirs rather than through cfg.FindHelperBinary.
+func findFirmware() (codePath, varsTemplatePath string, err error) {
+	codePath, err = locateFirmwareFile(firmwareCodeFile())
+	if err != nil {
+		return "", "", fmt.Errorf("locating UEFI firmware code: %w", err)
+	}
+	varsTemplatePath, err = locateFirmwareFile(firmwareVarsTemplate())
+	if err != nil {
+		return "", "", fmt.Errorf("locating UEFI firmware VARS template: %w", err)
+	}
+	return codePath, varsTemplatePath, nil
+}
+
+// firmwareVarsPath returns the path of this machine's own copy of the VARS
+// template, created by setupFirmware during CreateVM.
+func firmwareVarsPath(runtimeDirPath, name string) string {
+	return filepath.Join(runtimeDirPath, name+"_VARS.fd")
+}
+
+// setupFirmware copies the VARS template into the machine's runtime dir so
+// each machine gets its own mutable NVRAM store (Secure Boot enrollment,
+// boot order, etc. must not be shared between machines). It is a no-op for
+// legacy BIOS machines.
+func (q *QEMUStubber) setupFirmware(mc *vmconfigs.MachineConfig, runtimeDirPath string) error {
+	if mc.QEMUHypervisor.Firmware == "" || mc.QEMUHypervisor.Firmware == vmconfigs.BIOS {
+		return nil
+	}
+
+	_, varsTemplatePath, err := findFirmware()
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(varsTemplatePath)
+	if err != nil {
+		return fmt.Errorf("opening UEFI VARS template: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(firmwareVarsPath(runtimeDirPath, mc.Name))
+	if err != nil {
+		return fmt.Errorf("creating machine VARS store: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("copying UEFI VARS template: %w", err)
+	}
+	return nil
+}
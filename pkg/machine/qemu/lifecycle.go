@@ -0,0 +1,137 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/podman/v5/pkg/machine/qemu/command"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/sirupsen/logrus"
+)
+
+// qemuShutdownTimeout bounds how long StopVM waits for the guest to
+// acknowledge an ACPI power-down before falling back to SIGTERM.
+var qemuShutdownTimeout = 20 * time.Second
+
+func (q *QEMUStubber) qmpClient(mc *vmconfigs.MachineConfig) (*command.QMPClient, error) {
+	return command.NewQMPClient(mc.QEMUHypervisor.QMPMonitor.Address.GetPath())
+}
+
+// StopVM shuts the machine down. By default it asks the guest to power
+// itself off over QMP, which gives it a chance to unmount cleanly; it
+// falls back to SIGTERM if the guest does not exit within
+// qemuShutdownTimeout. hardStop skips straight to SIGTERM.
+func (q *QEMUStubber) StopVM(mc *vmconfigs.MachineConfig, hardStop bool) error {
+	if hardStop {
+		if err := q.killQEMU(mc); err != nil {
+			return err
+		}
+		return q.stopSidecars(mc)
+	}
+
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.PowerDown(); err != nil {
+		return err
+	}
+
+	pid, err := readPidFile(mc.QEMUHypervisor.QEMUPidPath.GetPath())
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(qemuShutdownTimeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			return q.stopSidecars(mc)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	logrus.Warnf("qemu pid %d did not shut down within %s of ACPI power-down, sending SIGTERM", pid, qemuShutdownTimeout)
+	if err := q.killQEMU(mc); err != nil {
+		return err
+	}
+	return q.stopSidecars(mc)
+}
+
+// stopSidecars tears down the per-mount virtiofsd processes and the
+// swtpm process (if any) started alongside qemu, so a normal "machine
+// stop" doesn't leave them running and holding onto their sockets.
+func (q *QEMUStubber) stopSidecars(mc *vmconfigs.MachineConfig) error {
+	runtimeDir, err := mc.RuntimeDir()
+	if err != nil {
+		return err
+	}
+	runtimeDirPath := runtimeDir.GetPath()
+
+	if err := stopVirtiofsd(runtimeDirPath, mc.Name); err != nil {
+		logrus.Warnf("stopping virtiofsd: %v", err)
+	}
+	if err := stopSwtpm(runtimeDirPath, mc.Name); err != nil {
+		logrus.Warnf("stopping swtpm: %v", err)
+	}
+	return nil
+}
+
+// Pause freezes a running VM in place via QMP without shutting it down.
+func (q *QEMUStubber) Pause(mc *vmconfigs.MachineConfig) error {
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Stop()
+}
+
+// Resume unfreezes a VM previously frozen with Pause.
+func (q *QEMUStubber) Resume(mc *vmconfigs.MachineConfig) error {
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Cont()
+}
+
+func (q *QEMUStubber) killQEMU(mc *vmconfigs.MachineConfig) error {
+	pid, err := readPidFile(mc.QEMUHypervisor.QEMUPidPath.GetPath())
+	if err != nil {
+		return err
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Signal(syscall.SIGTERM)
+}
+
+func readPidFile(path string) (int, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid pidfile %q: %w", path, err)
+	}
+	return pid, nil
+}
+
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,103 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+)
+
+// Snapshot saves the VM's current state under name, stored directly in the
+// qcow2 image via QMP's savevm passthrough.
+func (q *QEMUStubber) Snapshot(mc *vmconfigs.MachineConfig, name string) error {
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if _, err := client.HumanMonitorCommand("savevm " + name); err != nil {
+		return fmt.Errorf("saving snapshot %q: %w", name, err)
+	}
+	return nil
+}
+
+// Restore rolls the VM back to a previously saved snapshot.
+func (q *QEMUStubber) Restore(mc *vmconfigs.MachineConfig, name string) error {
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if _, err := client.HumanMonitorCommand("loadvm " + name); err != nil {
+		return fmt.Errorf("restoring snapshot %q: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveSnapshot deletes a previously saved snapshot from the qcow2 image.
+func (q *QEMUStubber) RemoveSnapshot(mc *vmconfigs.MachineConfig, name string) error {
+	client, err := q.qmpClient(mc)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if _, err := client.HumanMonitorCommand("delvm " + name); err != nil {
+		return fmt.Errorf("removing snapshot %q: %w", name, err)
+	}
+	return nil
+}
+
+// ListSnapshots enumerates the snapshots stored in the VM's qcow2 image.
+// Unlike Snapshot/Restore/RemoveSnapshot this does not require the VM to be
+// running, since it shells out to qemu-img rather than going through QMP.
+func (q *QEMUStubber) ListSnapshots(mc *vmconfigs.MachineConfig) ([]string, error) {
+	cfg, err := config.Default()
+	if err != nil {
+		return nil, err
+	}
+	qemuImgPath, err := cfg.FindHelperBinary("qemu-img", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(qemuImgPath, "snapshot", "-l", mc.ImagePath.GetPath())
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	return parseQemuImgSnapshotList(&stdout), nil
+}
+
+// parseQemuImgSnapshotList extracts snapshot names (tags) from the table
+// printed by `qemu-img snapshot -l`, e.g.:
+//
+//	Snapshot list:
+//	ID        TAG                 VM SIZE                DATE       VM CLOCK
+//	1         before-upgrade      0 B 2024-01-01 00:00:00   00:00:00.000
+func parseQemuImgSnapshotList(output *bytes.Buffer) []string {
+	var snapshots []string
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Snapshot list:") || strings.HasPrefix(line, "ID") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		snapshots = append(snapshots, fields[1])
+	}
+	return snapshots
+}
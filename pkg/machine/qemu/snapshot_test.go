@@ -0,0 +1,47 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseQemuImgSnapshotList(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name:   "no snapshots",
+			output: "",
+			want:   nil,
+		},
+		{
+			name: "single snapshot",
+			output: "Snapshot list:\n" +
+				"ID        TAG                 VM SIZE                DATE       VM CLOCK\n" +
+				"1         before-upgrade      0 B 2024-01-01 00:00:00   00:00:00.000\n",
+			want: []string{"before-upgrade"},
+		},
+		{
+			name: "multiple snapshots",
+			output: "Snapshot list:\n" +
+				"ID        TAG                 VM SIZE                DATE       VM CLOCK\n" +
+				"1         before-upgrade      0 B 2024-01-01 00:00:00   00:00:00.000\n" +
+				"2         after-upgrade       0 B 2024-01-02 00:00:00   00:00:00.000\n",
+			want: []string{"before-upgrade", "after-upgrade"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseQemuImgSnapshotList(bytes.NewBufferString(tt.output))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQemuImgSnapshotList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -84,14 +84,60 @@ func (q *QEMUStubber) setQEMUCommandLine(mc *vmconfigs.MachineConfig) error {
 	q.Command.SetSerialPort(*readySocket, *mc.QEMUHypervisor.QEMUPidPath, mc.Name)
 
 	// Add volumes to qemu command line
+	runtimeDir, err := mc.RuntimeDir()
+	if err != nil {
+		return err
+	}
+	runtimeDirPath := runtimeDir.GetPath()
+	sharedMemoryEnabled := false
 	for _, mount := range mc.Mounts {
-		// the index provided in this case is thrown away
-		_, _, _, _, securityModel := vmconfigs.SplitVolume(0, mount.OriginalInput)
-		q.Command.SetVirtfsMount(mount.Source, mount.Tag, securityModel, mount.ReadOnly)
+		// the --volume parser doesn't thread type= through to mount.Type,
+		// so reparse the option string here to know which mount type the
+		// user actually asked for
+		switch vmconfigs.ParseMountType(mount.OriginalInput) {
+		case vmconfigs.VirtIOFS:
+			if !sharedMemoryEnabled {
+				q.Command.EnableVirtioFSMemory(mc.Resources.Memory)
+				sharedMemoryEnabled = true
+			}
+			q.Command.SetVirtioFSMount(mount.Tag, virtiofsSocket(runtimeDirPath, mc.Name, mount.Tag))
+		default:
+			// the index provided in this case is thrown away
+			_, _, _, _, securityModel := vmconfigs.SplitVolume(0, mount.OriginalInput)
+			q.Command.SetVirtfsMount(mount.Source, mount.Tag, securityModel, mount.ReadOnly)
+		}
 	}
 
 	q.Command.SetUSBHostPassthrough(mc.Resources.USBs)
 
+	q.setDisksCommandLine(mc)
+
+	if err := q.setFirmwareCommandLine(mc, runtimeDirPath); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setFirmwareCommandLine adds the UEFI pflash drives and, for uefi-secure,
+// the emulated TPM device, on top of the legacy BIOS command line built
+// above. It is a no-op for vmconfigs.BIOS (or unset) machines.
+func (q *QEMUStubber) setFirmwareCommandLine(mc *vmconfigs.MachineConfig, runtimeDirPath string) error {
+	firmware := mc.QEMUHypervisor.Firmware
+	if firmware == "" || firmware == vmconfigs.BIOS {
+		return nil
+	}
+
+	codePath, _, err := findFirmware()
+	if err != nil {
+		return err
+	}
+	q.Command.SetFirmware(codePath, firmwareVarsPath(runtimeDirPath, mc.Name))
+
+	if firmware == vmconfigs.UEFISecure {
+		q.Command.SetTPM(swtpmSocket(runtimeDirPath, mc.Name), tpmDeviceModel())
+	}
+
 	return nil
 }
 
@@ -116,6 +162,19 @@ func (q *QEMUStubber) CreateVM(opts define.CreateVMOpts, mc *vmconfigs.MachineCo
 
 	mc.QEMUHypervisor = &qemuConfig
 	mc.QEMUHypervisor.QEMUPidPath = qemuPidPath
+
+	// opts.Firmware is empty when the caller did not choose a firmware, so
+	// default it to legacy BIOS rather than leaving it as an empty
+	// vmconfigs.Firmware value.
+	mc.QEMUHypervisor.Firmware = opts.Firmware
+	if mc.QEMUHypervisor.Firmware == "" {
+		mc.QEMUHypervisor.Firmware = vmconfigs.BIOS
+	}
+
+	if err := q.setupFirmware(mc, opts.Dirs.RuntimeDir.GetPath()); err != nil {
+		return err
+	}
+
 	return q.resizeDisk(strongunits.GiB(mc.Resources.DiskSize), mc.ImagePath)
 }
 
@@ -142,6 +201,17 @@ func runStartVMCommand(cmd *exec.Cmd) error {
 }
 
 func (q *QEMUStubber) StartVM(mc *vmconfigs.MachineConfig) (func() error, func() error, error) {
+	// virtiofsd must be listening on its socket(s) before the qemu command
+	// line below tries to dial them
+	if err := q.startVirtiofsd(mc); err != nil {
+		return nil, nil, fmt.Errorf("unable to start virtiofsd: %w", err)
+	}
+
+	// likewise for swtpm on uefi-secure machines
+	if err := q.startSwtpm(mc); err != nil {
+		return nil, nil, fmt.Errorf("unable to start swtpm: %w", err)
+	}
+
 	if err := q.setQEMUCommandLine(mc); err != nil {
 		return nil, nil, fmt.Errorf("unable to generate qemu command line: %q", err)
 	}
@@ -264,6 +334,19 @@ func (q *QEMUStubber) SetProviderAttrs(mc *vmconfigs.MachineConfig, opts define.
 		if err := q.resizeDisk(*opts.DiskSize, mc.ImagePath); err != nil {
 			return err
 		}
+
+		// Additional disks created smaller than the new boot disk size are
+		// grown to match; ones already at or above it are left alone, since
+		// qemu-img resize can only grow an image, not shrink it.
+		for i, disk := range mc.Disks {
+			if strongunits.GiB(disk.Size) >= *opts.DiskSize {
+				continue
+			}
+			if err := q.resizeDisk(*opts.DiskSize, disk.Path); err != nil {
+				return err
+			}
+			mc.Disks[i].Size = uint64(*opts.DiskSize)
+		}
 	}
 
 	if opts.Rootful != nil && mc.HostUser.Rootful != *opts.Rootful {
@@ -297,8 +380,34 @@ func (q *QEMUStubber) StartNetworking(mc *vmconfigs.MachineConfig, cmd *gvproxy.
 	return nil
 }
 
-func (q *QEMUStubber) RemoveAndCleanMachines(_ *define.MachineDirs) error {
-	// nothing to do but remove files
+func (q *QEMUStubber) RemoveAndCleanMachines(dirs *define.MachineDirs) error {
+	// stray virtiofsd processes are not cleaned up by killing qemu, so reap
+	// them here based on the pidfiles left in the runtime dir
+	matches, err := filepath.Glob(filepath.Join(dirs.RuntimeDir.GetPath(), "*_virtiofsd_*.pid"))
+	if err != nil {
+		return err
+	}
+	for _, pidFile := range matches {
+		name := strings.TrimSuffix(filepath.Base(pidFile), filepath.Ext(pidFile))
+		if idx := strings.Index(name, "_virtiofsd_"); idx >= 0 {
+			name = name[:idx]
+		}
+		if err := stopVirtiofsd(dirs.RuntimeDir.GetPath(), name); err != nil {
+			logrus.Warnf("stopping virtiofsd: %v", err)
+		}
+	}
+
+	swtpmMatches, err := filepath.Glob(filepath.Join(dirs.RuntimeDir.GetPath(), "*_swtpm.pid"))
+	if err != nil {
+		return err
+	}
+	for _, pidFile := range swtpmMatches {
+		name := strings.TrimSuffix(filepath.Base(pidFile), "_swtpm.pid")
+		if err := stopSwtpm(dirs.RuntimeDir.GetPath(), name); err != nil {
+			logrus.Warnf("stopping swtpm: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -325,7 +434,7 @@ func (q *QEMUStubber) MountVolumesToVM(mc *vmconfigs.MachineConfig, quiet bool)
 		if err != nil {
 			return err
 		}
-		switch mount.Type {
+		switch vmconfigs.ParseMountType(mount.OriginalInput) {
 		case MountType9p:
 			mountOptions := []string{"-t", "9p"}
 			mountOptions = append(mountOptions, []string{"-o", "trans=virtio", mount.Tag, mount.Target}...)
@@ -337,8 +446,17 @@ func (q *QEMUStubber) MountVolumesToVM(mc *vmconfigs.MachineConfig, quiet bool)
 			if err != nil {
 				return err
 			}
+		case MountTypeVirtiofs:
+			mountOptions := []string{"-t", "virtiofs", mount.Tag, mount.Target}
+			if mount.ReadOnly {
+				mountOptions = append(mountOptions, []string{"-o", "ro"}...)
+			}
+			err = machine.CommonSSH(mc.SSH.RemoteUsername, mc.SSH.IdentityPath, mc.Name, mc.SSH.Port, append([]string{"-q", "--", "sudo", "mount"}, mountOptions...))
+			if err != nil {
+				return err
+			}
 		default:
-			return fmt.Errorf("unknown mount type: %s", mount.Type)
+			return fmt.Errorf("unknown mount type: %s", vmconfigs.ParseMountType(mount.OriginalInput))
 		}
 	}
 	return nil
@@ -0,0 +1,99 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/sirupsen/logrus"
+)
+
+const swtpmBinary = "swtpm"
+
+func swtpmSocket(runtimeDirPath, name string) string {
+	return filepath.Join(runtimeDirPath, name+"_swtpm.sock")
+}
+
+func swtpmStateDir(runtimeDirPath, name string) string {
+	return filepath.Join(runtimeDirPath, name+"_swtpm-state")
+}
+
+func swtpmPidFile(runtimeDirPath, name string) string {
+	return filepath.Join(runtimeDirPath, name+"_swtpm.pid")
+}
+
+// startSwtpm spawns swtpm in socket mode, tracked via pidfile exactly like
+// gvproxy and virtiofsd, so a Secure Boot machine has a TPM ready for qemu
+// to attach to by the time the command line is built.
+func (q *QEMUStubber) startSwtpm(mc *vmconfigs.MachineConfig) error {
+	if mc.QEMUHypervisor.Firmware != vmconfigs.UEFISecure {
+		return nil
+	}
+
+	runtimeDir, err := mc.RuntimeDir()
+	if err != nil {
+		return err
+	}
+	runtimeDirPath := runtimeDir.GetPath()
+
+	stateDir := swtpmStateDir(runtimeDirPath, mc.Name)
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return fmt.Errorf("creating swtpm state dir: %w", err)
+	}
+
+	socketPath := swtpmSocket(runtimeDirPath, mc.Name)
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale swtpm socket: %w", err)
+	}
+
+	cfg, err := config.Default()
+	if err != nil {
+		return err
+	}
+	binary, err := cfg.FindHelperBinary(swtpmBinary, true)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(binary, "socket",
+		"--tpmstate", "dir="+stateDir,
+		"--ctrl", "type=unixio,path="+socketPath,
+		"--pid", "file="+swtpmPidFile(runtimeDirPath, mc.Name),
+		"--tpm2",
+		"--daemon",
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting swtpm: %w", err)
+	}
+	logrus.Debugf("started swtpm on %s", socketPath)
+	return cmd.Wait()
+}
+
+// stopSwtpm terminates the swtpm process belonging to the named machine,
+// identified by its pidfile in runtimeDirPath.
+func stopSwtpm(runtimeDirPath, name string) error {
+	pidFile := swtpmPidFile(runtimeDirPath, name)
+	if _, err := os.Stat(pidFile); os.IsNotExist(err) {
+		return nil
+	}
+
+	pid, err := readPidFile(pidFile)
+	if err != nil {
+		return err
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	if err := proc.Kill(); err != nil {
+		logrus.Warnf("killing swtpm pid %d: %v", pid, err)
+	}
+	return os.Remove(pidFile)
+}
@@ -0,0 +1,128 @@
+//go:build !darwin
+
+package qemu
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+	"github.com/sirupsen/logrus"
+)
+
+const virtiofsdBinary = "virtiofsd"
+
+// MountTypeVirtiofs mirrors vmconfigs.VirtIOFS for use in the local mount
+// type switches alongside MountType9p.
+const MountTypeVirtiofs = vmconfigs.VirtIOFS
+
+// virtiofsSocket returns the vhost-user socket virtiofsd listens on for a
+// given mount tag.
+func virtiofsSocket(runtimeDirPath, name, tag string) string {
+	return filepath.Join(runtimeDirPath, fmt.Sprintf("%s_virtiofsd_%s.sock", name, tag))
+}
+
+func virtiofsPidFile(runtimeDirPath, name, tag string) string {
+	return filepath.Join(runtimeDirPath, fmt.Sprintf("%s_virtiofsd_%s.pid", name, tag))
+}
+
+// startVirtiofsd spawns one virtiofsd process per virtiofs mount, each
+// listening on its own vhost-user socket, before qemu is started so the
+// command line below can dial those sockets immediately.
+func (q *QEMUStubber) startVirtiofsd(mc *vmconfigs.MachineConfig) error {
+	runtimeDir, err := mc.RuntimeDir()
+	if err != nil {
+		return err
+	}
+	runtimeDirPath := runtimeDir.GetPath()
+
+	cfg, err := config.Default()
+	if err != nil {
+		return err
+	}
+	binary, err := cfg.FindHelperBinary(virtiofsdBinary, true)
+	if err != nil {
+		return err
+	}
+
+	for _, mount := range mc.Mounts {
+		// mount.Type isn't populated by the --volume parser (see
+		// vmconfigs.ParseMountType's doc comment), so, like
+		// setQEMUCommandLine and MountVolumesToVM, re-derive the mount type
+		// from the original option string instead of trusting the field.
+		if vmconfigs.ParseMountType(mount.OriginalInput) != vmconfigs.VirtIOFS {
+			continue
+		}
+
+		socketPath := virtiofsSocket(runtimeDirPath, mc.Name, mount.Tag)
+		pidFilePath := virtiofsPidFile(runtimeDirPath, mc.Name, mount.Tag)
+		// virtiofsd refuses to start if a stale socket is left behind
+		if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing stale virtiofsd socket: %w", err)
+		}
+
+		args := []string{
+			"--socket-path", socketPath,
+			"--shared-dir", mount.Source,
+			"--pid-file", pidFilePath,
+		}
+		if mount.ReadOnly {
+			args = append(args, "-o", "readonly")
+		}
+
+		cmd := exec.Command(binary, args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("starting virtiofsd for %q: %w", mount.Tag, err)
+		}
+		logrus.Debugf("started virtiofsd pid %d for tag %q on %s", cmd.Process.Pid, mount.Tag, socketPath)
+		// virtiofsd daemonizes itself; we only need the pidfile afterward for teardown
+		if err := cmd.Process.Release(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stopVirtiofsd terminates any virtiofsd processes belonging to the named
+// machine, identified by the pidfiles left in runtimeDirPath.
+func stopVirtiofsd(runtimeDirPath, name string) error {
+	matches, err := filepath.Glob(filepath.Join(runtimeDirPath, name+"_virtiofsd_*.pid"))
+	if err != nil {
+		return err
+	}
+
+	for _, pidFile := range matches {
+		raw, err := os.ReadFile(pidFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil {
+			logrus.Warnf("invalid virtiofsd pidfile %q: %v", pidFile, err)
+			continue
+		}
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		if err := proc.Kill(); err != nil && !strings.Contains(err.Error(), "process already finished") {
+			logrus.Warnf("killing virtiofsd pid %d: %v", pid, err)
+		}
+		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("removing virtiofsd pidfile %q: %v", pidFile, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package shim
+
+import (
+	"fmt"
+
+	"github.com/containers/common/pkg/strongunits"
+	"github.com/containers/podman/v5/pkg/machine/define"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+)
+
+// InitOptions carries the subset of "podman machine init" flags this
+// package threads down into vmconfigs.MachineConfig and define.CreateVMOpts.
+type InitOptions struct {
+	Name     string
+	CPUs     uint64
+	MemoryMB uint64
+	DiskGB   uint64
+	Rootful  bool
+	Firmware vmconfigs.Firmware
+	TPM      bool
+}
+
+// Init builds a new machine's config and hands it to provider's CreateVM,
+// the same entry point every VMStubber implements.
+func Init(opts InitOptions, dirs *define.MachineDirs, provider vmconfigs.VMStubber) (*vmconfigs.MachineConfig, error) {
+	if opts.TPM && opts.Firmware != vmconfigs.UEFISecure {
+		return nil, fmt.Errorf("--tpm requires --firmware=%s", vmconfigs.UEFISecure)
+	}
+
+	mc := &vmconfigs.MachineConfig{
+		Name: opts.Name,
+	}
+	mc.Resources.CPUs = opts.CPUs
+	mc.Resources.Memory = strongunits.MiB(opts.MemoryMB)
+	mc.Resources.DiskSize = opts.DiskGB
+
+	createOpts := define.CreateVMOpts{
+		Name:     opts.Name,
+		Dirs:     dirs,
+		Firmware: opts.Firmware,
+	}
+
+	if err := provider.CreateVM(createOpts, mc, nil); err != nil {
+		return nil, err
+	}
+
+	if opts.Rootful {
+		if err := mc.SetRootful(true); err != nil {
+			return nil, err
+		}
+	}
+
+	return mc, nil
+}
@@ -0,0 +1,51 @@
+package shim
+
+import (
+	"fmt"
+
+	"github.com/containers/podman/v5/pkg/machine/define"
+	"github.com/containers/podman/v5/pkg/machine/vmconfigs"
+)
+
+// pausable is implemented by providers (currently only QEMUStubber) that
+// can freeze a running VM in place over their control channel without
+// shutting it down.
+type pausable interface {
+	Pause(mc *vmconfigs.MachineConfig) error
+	Resume(mc *vmconfigs.MachineConfig) error
+}
+
+// Pause freezes a running machine, mirroring the running-state check Stop
+// already does before it is allowed to act.
+func Pause(mc *vmconfigs.MachineConfig, provider vmconfigs.VMStubber) error {
+	state, err := provider.State(mc, false)
+	if err != nil {
+		return err
+	}
+	if state != define.Running {
+		return fmt.Errorf("unable to pause %q: machine is not running", mc.Name)
+	}
+
+	p, ok := provider.(pausable)
+	if !ok {
+		return fmt.Errorf("pause is not supported by the %q provider", provider.VMType())
+	}
+	return p.Pause(mc)
+}
+
+// Resume unfreezes a machine previously frozen with Pause.
+func Resume(mc *vmconfigs.MachineConfig, provider vmconfigs.VMStubber) error {
+	state, err := provider.State(mc, false)
+	if err != nil {
+		return err
+	}
+	if state != define.Running {
+		return fmt.Errorf("unable to resume %q: machine is not running", mc.Name)
+	}
+
+	p, ok := provider.(pausable)
+	if !ok {
+		return fmt.Errorf("resume is not supported by the %q provider", provider.VMType())
+	}
+	return p.Resume(mc)
+}
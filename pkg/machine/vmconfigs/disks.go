@@ -0,0 +1,37 @@
+package vmconfigs
+
+import "github.com/containers/podman/v5/pkg/machine/define"
+
+// DiskBus selects the virtual bus an additional disk is attached to.
+type DiskBus string
+
+const (
+	VirtioBlkBus DiskBus = "virtio-blk"
+	NVMeBus      DiskBus = "nvme"
+	SCSIBus      DiskBus = "scsi"
+)
+
+// DiskFormat is the on-disk image format of an additional disk.
+type DiskFormat string
+
+const (
+	Qcow2 DiskFormat = "qcow2"
+	Raw   DiskFormat = "raw"
+	Vmdk  DiskFormat = "vmdk"
+	Vhdx  DiskFormat = "vhdx"
+)
+
+// DiskConfig describes one additional disk attached to a machine beyond its
+// boot image, as created by `podman machine disk add`.
+type DiskConfig struct {
+	Name     string
+	Path     *define.VMFile
+	Size     uint64 // GiB
+	Format   DiskFormat
+	Cache    string
+	AIO      string
+	Discard  bool
+	Serial   string
+	ReadOnly bool
+	Bus      DiskBus
+}
@@ -0,0 +1,11 @@
+package vmconfigs
+
+// Firmware selects what the machine boots: legacy BIOS, UEFI, or UEFI with
+// Secure Boot enforced.
+type Firmware string
+
+const (
+	BIOS       Firmware = "bios"
+	UEFI       Firmware = "uefi"
+	UEFISecure Firmware = "uefi-secure"
+)